@@ -0,0 +1,215 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Metadata operator types
+const (
+	RegexParser    = "regex_parser"
+	JSONParser     = "json_parser"
+	KeyValueParser = "key_value_parser"
+)
+
+// HeaderConfig describes how to pull static attributes out of the header lines
+// that precede the actual payload in some files (CEF, syslog preambles, CSV
+// column headers, custom app banners, ...). Pattern identifies the lines that
+// belong to the header, and MetadataOperators are run in order, via Apply, on
+// the concatenated header to produce the resulting attributes.
+//
+// This is config schema plus the pure parsing pipeline (Apply) only. Nothing
+// in this tree yet buffers header lines on first file open, attaches the
+// resulting attributes as tags on subsequent log lines, discards the header
+// lines, or persists/reapplies attributes across restarts and file rotations
+// — there is no file tailer in this codebase at all. That runtime wiring is
+// unimplemented and tracked as follow-up work, not something this package or
+// any other part of this series delivers.
+type HeaderConfig struct {
+	Pattern           string              `mapstructure:"pattern" json:"pattern" toml:"pattern"`
+	MetadataOperators []*MetadataOperator `mapstructure:"metadata_operators" json:"metadata_operators" toml:"metadata_operators"`
+}
+
+// MetadataOperator describes a single parsing step applied to the header of a
+// file. Only the fields relevant to Type are used.
+type MetadataOperator struct {
+	Type string `mapstructure:"type" json:"type" toml:"type"`
+
+	// Regex, used by RegexParser. Named capture groups become attribute keys.
+	Regex string `mapstructure:"regex" json:"regex" toml:"regex"`
+
+	// ParseFrom is a regex selecting the part of the header this operator reads:
+	// when set, the operator parses the first capture group of ParseFrom's
+	// match (the whole match if it has no groups) instead of the full header.
+	// "" means the whole concatenated header.
+	ParseFrom string `mapstructure:"parse_from" json:"parse_from" toml:"parse_from"`
+
+	// Fields, used by every operator type, selects and renames parsed
+	// keys to the attribute names attached to the log line: a parsed key not
+	// present in Fields is dropped. An empty map keeps every parsed key as-is.
+	Fields map[string]string `mapstructure:"fields" json:"fields" toml:"fields"`
+
+	// Delimiter and PairDelimiter are used by KeyValueParser, e.g. "=" and " "
+	// to parse `key1=val1 key2=val2`.
+	Delimiter     string `mapstructure:"delimiter" json:"delimiter" toml:"delimiter"`
+	PairDelimiter string `mapstructure:"pair_delimiter" json:"pair_delimiter" toml:"pair_delimiter"`
+}
+
+// Validate returns an error if the header config is misconfigured.
+func (h *HeaderConfig) Validate() error {
+	if h.Pattern == "" {
+		return fmt.Errorf("header config must have a pattern")
+	}
+	if _, err := regexp.Compile(h.Pattern); err != nil {
+		return fmt.Errorf("invalid header pattern %q: %w", h.Pattern, err)
+	}
+	if len(h.MetadataOperators) == 0 {
+		return fmt.Errorf("header config must have at least one metadata operator")
+	}
+	for _, op := range h.MetadataOperators {
+		if err := op.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if the metadata operator is misconfigured.
+func (o *MetadataOperator) Validate() error {
+	if o.ParseFrom != "" {
+		if _, err := regexp.Compile(o.ParseFrom); err != nil {
+			return fmt.Errorf("invalid parse_from %q: %w", o.ParseFrom, err)
+		}
+	}
+	switch o.Type {
+	case RegexParser:
+		if o.Regex == "" {
+			return fmt.Errorf("%s operator must have a regex", RegexParser)
+		}
+		if _, err := regexp.Compile(o.Regex); err != nil {
+			return fmt.Errorf("invalid regex %q for %s operator: %w", o.Regex, RegexParser, err)
+		}
+	case JSONParser:
+		// no mandatory fields, the operator's input (the whole header, or the
+		// part ParseFrom selects) is expected to be a JSON object
+	case KeyValueParser:
+		if o.Delimiter == "" {
+			return fmt.Errorf("%s operator must have a delimiter", KeyValueParser)
+		}
+	default:
+		return fmt.Errorf("unknown metadata operator type %q", o.Type)
+	}
+	return nil
+}
+
+// Apply runs the operator pipeline against the concatenated header lines and
+// returns the resulting attributes, merging the output of each operator in
+// order. Nothing in this codebase calls Apply yet: deciding when a header is
+// complete, attaching the returned attributes to subsequent log lines, and
+// persisting/reapplying them across restarts and file rotations would be a
+// file tailer's job, and no such tailer exists in this tree.
+func (h *HeaderConfig) Apply(header string) (map[string]string, error) {
+	attrs := make(map[string]string)
+	for _, op := range h.MetadataOperators {
+		if err := op.apply(header, attrs); err != nil {
+			return nil, err
+		}
+	}
+	return attrs, nil
+}
+
+// apply runs a single operator against header, merging its output into attrs.
+func (o *MetadataOperator) apply(header string, attrs map[string]string) error {
+	input, err := o.resolveInput(header)
+	if err != nil {
+		return err
+	}
+	switch o.Type {
+	case RegexParser:
+		re, err := regexp.Compile(o.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex %q for %s operator: %w", o.Regex, RegexParser, err)
+		}
+		names := re.SubexpNames()
+		match := re.FindStringSubmatch(input)
+		if match == nil {
+			return fmt.Errorf("%s operator: input does not match regex %q", RegexParser, o.Regex)
+		}
+		for i, name := range names {
+			if name == "" {
+				continue
+			}
+			o.setAttr(attrs, name, match[i])
+		}
+	case JSONParser:
+		dec := json.NewDecoder(strings.NewReader(input))
+		dec.UseNumber()
+		var parsed map[string]interface{}
+		if err := dec.Decode(&parsed); err != nil {
+			return fmt.Errorf("%s operator: %w", JSONParser, err)
+		}
+		for k, v := range parsed {
+			o.setAttr(attrs, k, fmt.Sprintf("%v", v))
+		}
+	case KeyValueParser:
+		pairDelim := o.PairDelimiter
+		if pairDelim == "" {
+			pairDelim = " "
+		}
+		for _, pair := range strings.Split(input, pairDelim) {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			kv := strings.SplitN(pair, o.Delimiter, 2)
+			if len(kv) != 2 {
+				continue
+			}
+			o.setAttr(attrs, strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]))
+		}
+	default:
+		return fmt.Errorf("unknown metadata operator type %q", o.Type)
+	}
+	return nil
+}
+
+// resolveInput returns the substring of header this operator actually parses:
+// the whole header, or, when ParseFrom is set, the first capture group of its
+// match (the whole match if ParseFrom has no groups).
+func (o *MetadataOperator) resolveInput(header string) (string, error) {
+	if o.ParseFrom == "" {
+		return header, nil
+	}
+	re, err := regexp.Compile(o.ParseFrom)
+	if err != nil {
+		return "", fmt.Errorf("invalid parse_from %q: %w", o.ParseFrom, err)
+	}
+	match := re.FindStringSubmatch(header)
+	if match == nil {
+		return "", fmt.Errorf("parse_from %q does not match header", o.ParseFrom)
+	}
+	if len(match) > 1 {
+		return match[1], nil
+	}
+	return match[0], nil
+}
+
+// setAttr writes key/value into attrs, applying the operator's Fields remap
+// and dropping the key entirely if it is mapped to "".
+func (o *MetadataOperator) setAttr(attrs map[string]string, key, value string) {
+	if len(o.Fields) > 0 {
+		mapped, ok := o.Fields[key]
+		if !ok || mapped == "" {
+			return
+		}
+		key = mapped
+	}
+	attrs[key] = value
+}
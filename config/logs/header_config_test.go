@@ -0,0 +1,194 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logs
+
+import "testing"
+
+func TestHeaderConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  HeaderConfig
+		wantErr bool
+	}{
+		{
+			name:    "missing pattern",
+			config:  HeaderConfig{MetadataOperators: []*MetadataOperator{{Type: JSONParser}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			config:  HeaderConfig{Pattern: "(", MetadataOperators: []*MetadataOperator{{Type: JSONParser}}},
+			wantErr: true,
+		},
+		{
+			name:    "missing operators",
+			config:  HeaderConfig{Pattern: "^#"},
+			wantErr: true,
+		},
+		{
+			name:    "valid",
+			config:  HeaderConfig{Pattern: "^#", MetadataOperators: []*MetadataOperator{{Type: JSONParser}}},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMetadataOperatorValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      MetadataOperator
+		wantErr bool
+	}{
+		{
+			name:    "regex_parser missing regex",
+			op:      MetadataOperator{Type: RegexParser},
+			wantErr: true,
+		},
+		{
+			name:    "regex_parser invalid regex",
+			op:      MetadataOperator{Type: RegexParser, Regex: "("},
+			wantErr: true,
+		},
+		{
+			name:    "regex_parser valid",
+			op:      MetadataOperator{Type: RegexParser, Regex: "(?P<host>.*)"},
+			wantErr: false,
+		},
+		{
+			name:    "json_parser valid",
+			op:      MetadataOperator{Type: JSONParser},
+			wantErr: false,
+		},
+		{
+			name:    "key_value_parser missing delimiter",
+			op:      MetadataOperator{Type: KeyValueParser},
+			wantErr: true,
+		},
+		{
+			name:    "key_value_parser valid",
+			op:      MetadataOperator{Type: KeyValueParser, Delimiter: "="},
+			wantErr: false,
+		},
+		{
+			name:    "unknown operator type",
+			op:      MetadataOperator{Type: "bogus_parser"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.op.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHeaderConfigApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  HeaderConfig
+		header  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "regex_parser named groups",
+			config: HeaderConfig{
+				Pattern:           "^#",
+				MetadataOperators: []*MetadataOperator{{Type: RegexParser, Regex: `host=(?P<host>\S+) env=(?P<env>\S+)`}},
+			},
+			header: "# host=web-1 env=prod",
+			want:   map[string]string{"host": "web-1", "env": "prod"},
+		},
+		{
+			name: "json_parser with fields remap",
+			config: HeaderConfig{
+				Pattern: "^#",
+				MetadataOperators: []*MetadataOperator{{
+					Type:   JSONParser,
+					Fields: map[string]string{"host": "hostname"},
+				}},
+			},
+			header: `{"host": "web-1", "env": "prod"}`,
+			want:   map[string]string{"hostname": "web-1"},
+		},
+		{
+			name: "key_value_parser",
+			config: HeaderConfig{
+				Pattern: "^#",
+				MetadataOperators: []*MetadataOperator{{
+					Type:          KeyValueParser,
+					Delimiter:     "=",
+					PairDelimiter: " ",
+				}},
+			},
+			header: "host=web-1 env=prod",
+			want:   map[string]string{"host": "web-1", "env": "prod"},
+		},
+		{
+			name: "regex_parser no match",
+			config: HeaderConfig{
+				Pattern:           "^#",
+				MetadataOperators: []*MetadataOperator{{Type: RegexParser, Regex: `host=(?P<host>\S+)`}},
+			},
+			header:  "nothing useful here",
+			wantErr: true,
+		},
+		{
+			name: "json_parser preserves large integers",
+			config: HeaderConfig{
+				Pattern:           "^#",
+				MetadataOperators: []*MetadataOperator{{Type: JSONParser}},
+			},
+			header: `{"request_id": 100000000000000000}`,
+			want:   map[string]string{"request_id": "100000000000000000"},
+		},
+		{
+			name: "parse_from restricts the operator to a sub-section of the header",
+			config: HeaderConfig{
+				Pattern: "^#",
+				MetadataOperators: []*MetadataOperator{{
+					Type:      JSONParser,
+					ParseFrom: `^prefix: (\{.*\})$`,
+				}},
+			},
+			header: `prefix: {"host": "web-1"}`,
+			want:   map[string]string{"host": "web-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.Apply(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("Apply()[%q] = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
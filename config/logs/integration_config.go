@@ -7,6 +7,7 @@ package logs
 
 import (
 	"fmt"
+	"os"
 	"strings"
 )
 
@@ -16,6 +17,7 @@ const (
 	UDPType           = "udp"
 	FileType          = "file"
 	DockerType        = "docker"
+	KubernetesType    = "kubernetes"
 	JournaldType      = "journald"
 	WindowsEventType  = "windows_event"
 	SnmpTrapsType     = "snmp_traps"
@@ -26,6 +28,9 @@ const (
 	// UTF16LE for UTF-16 Little Endian encoding
 	UTF16LE string = "utf-16-le"
 
+	// DefaultPodLogsPath is the path LogsPath defaults to for the kubernetes source.
+	DefaultPodLogsPath = "/var/log/pods"
+
 	// https://en.wikipedia.org/wiki/GB_2312
 	// https://en.wikipedia.org/wiki/GBK_(character_encoding)
 	// https://en.wikipedia.org/wiki/GB_18030
@@ -51,6 +56,14 @@ type (
 		ExcludePaths []string `mapstructure:"exclude_paths" json:"exclude_paths" toml:"exclude_paths"`    // File
 		TailingMode  string   `mapstructure:"start_position" json:"start_position" toml:"start_position"` // File
 
+		// Header configures per-file header parsing (see HeaderConfig): which
+		// lines form the header and how to turn them into attributes via
+		// Header.Apply. Setting this field is schema-only today — no tailer in
+		// this codebase reads Header, buffers header lines, attaches the parsed
+		// attributes to subsequent log lines, or persists/reapplies them across
+		// restarts and rotations; that wiring is unimplemented follow-up work.
+		Header *HeaderConfig `mapstructure:"header" json:"header" toml:"header"` // File
+
 		IncludeUnits  []string `mapstructure:"include_units" json:"include_units" toml:"include_units"`    // Journald
 		ExcludeUnits  []string `mapstructure:"exclude_units" json:"exclude_units" toml:"exclude_units"`    // Journald
 		ContainerMode bool     `mapstructure:"container_mode" json:"container_mode" toml:"container_mode"` // Journald
@@ -65,6 +78,25 @@ type (
 		ChannelPath string `mapstructure:"channel_path" json:"channel_path" toml:"channel_path"` // Windows Event
 		Query       string // Windows Event
 
+		// Kubernetes config fields below describe a source meant to discover and
+		// tail container log files under LogsPath via the kubelet (or API
+		// server) pod list, using ParsePodLogLine/PartialLineBuffer to parse
+		// each line. This is schema only today: watching the pod list, directory
+		// discovery under LogsPath, and spawning/draining per-container tailers
+		// are unimplemented — nothing in this codebase performs them, so setting
+		// Type to KubernetesType currently has no runtime effect.
+		KubeletURL            string   `mapstructure:"kubelet_url" json:"kubelet_url" toml:"kubelet_url"`                                     // Kubernetes
+		LogsPath              string   `mapstructure:"logs_path" json:"logs_path" toml:"logs_path"`                                           // Kubernetes
+		IncludeNamespaces     []string `mapstructure:"include_namespaces" json:"include_namespaces" toml:"include_namespaces"`                // Kubernetes
+		ExcludeNamespaces     []string `mapstructure:"exclude_namespaces" json:"exclude_namespaces" toml:"exclude_namespaces"`                // Kubernetes
+		PodLabelSelector      string   `mapstructure:"pod_label_selector" json:"pod_label_selector" toml:"pod_label_selector"`                // Kubernetes
+		AnnotationBasedConfig bool     `mapstructure:"annotation_based_config" json:"annotation_based_config" toml:"annotation_based_config"` // Kubernetes
+		// Enrichment lists the pod metadata keys (e.g. pod_name, namespace,
+		// container_name, image, node, or arbitrary label/annotation names)
+		// meant to be promoted to tags on every tailed line once a launcher
+		// implements this source; unused until that launcher exists.
+		Enrichment []string `mapstructure:"enrichment" json:"enrichment" toml:"enrichment"` // Kubernetes
+
 		// used as input only by the Channel tailer.
 		// could have been unidirectional but the tailer could not close it in this case.
 		Channel chan *ChannelMessage `json:"-"`
@@ -138,6 +170,20 @@ func (c *LogsConfig) Validate() error {
 		if err != nil {
 			return err
 		}
+		if c.Header != nil {
+			if err := c.Header.Validate(); err != nil {
+				return err
+			}
+		}
+	case c.Type == KubernetesType:
+		if ContainsWildcard(c.Path) {
+			return fmt.Errorf("a wildcard path is not supported for the %v source, use include/exclude namespaces and a pod label selector instead", KubernetesType)
+		}
+		// an empty KubeletURL falls back to in-cluster discovery of the local
+		// kubelet endpoint, which is only possible when running inside a pod.
+		if c.KubeletURL == "" && !IsRunningInCluster() {
+			return fmt.Errorf("%v source must have a kubelet_url when not running in-cluster", KubernetesType)
+		}
 	case c.Type == TCPType && c.Port == 0:
 		return fmt.Errorf("tcp source must have a port")
 	case c.Type == UDPType && c.Port == 0:
@@ -165,3 +211,24 @@ func (c *LogsConfig) validateTailingMode() error {
 func ContainsWildcard(path string) bool {
 	return strings.ContainsAny(path, "*?[")
 }
+
+// LogsPathOrDefault returns LogsPath, or DefaultPodLogsPath if it is unset.
+// Validate leaves LogsPath untouched; callers that need the effective path
+// use this instead.
+func (c *LogsConfig) LogsPathOrDefault() string {
+	if c.LogsPath == "" {
+		return DefaultPodLogsPath
+	}
+	return c.LogsPath
+}
+
+// IsRunningInCluster returns true if categraf appears to be running inside a
+// kubernetes pod, in which case the kubelet endpoint can be discovered
+// automatically instead of requiring an explicit KubeletURL. It mirrors the
+// env-based half of client-go's rest.InClusterConfig heuristic; it
+// deliberately does not also require the service-account token file to exist,
+// since pods that set automountServiceAccountToken: false (or mount the token
+// elsewhere) are still running in-cluster.
+func IsRunningInCluster() bool {
+	return os.Getenv("KUBERNETES_SERVICE_HOST") != "" && os.Getenv("KUBERNETES_SERVICE_PORT") != ""
+}
@@ -0,0 +1,123 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logs
+
+// This file implements only the on-disk line framing used by kubernetes pod
+// log files (CRI and docker-json), plus partial-line stitching. It is pure
+// parsing logic with no caller anywhere in this codebase: there is no kubelet
+// or API-server watcher, no /var/log/pods discovery, and no per-container
+// tailer spawning/draining here or elsewhere in this tree. Building that
+// launcher to actually tail kubernetes pod logs is unimplemented follow-up
+// work, not something this file or the KubernetesType config delivers.
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kubernetes log line streams, as reported by both the CRI and docker-json
+// framings.
+const (
+	PodLogStreamStdout = "stdout"
+	PodLogStreamStderr = "stderr"
+)
+
+// PodLogLine is a single line read from a /var/log/pods/.../*.log file, once
+// its on-disk framing (CRI or docker-json) has been stripped away.
+type PodLogLine struct {
+	Time    time.Time
+	Stream  string
+	Partial bool
+	Content string
+}
+
+// dockerJSONLogLine matches the docker-json framing: one JSON object per line.
+type dockerJSONLogLine struct {
+	Log    string `json:"log"`
+	Stream string `json:"stream"`
+	Time   string `json:"time"`
+}
+
+// ParsePodLogLine parses a single raw line from a pod log file, detecting
+// whether it uses the CRI or docker-json framing.
+func ParsePodLogLine(line []byte) (*PodLogLine, error) {
+	if len(line) == 0 {
+		return nil, fmt.Errorf("empty log line")
+	}
+	if line[0] == '{' {
+		return parseDockerJSONLogLine(line)
+	}
+	return parseCRILogLine(line)
+}
+
+func parseDockerJSONLogLine(line []byte) (*PodLogLine, error) {
+	var raw dockerJSONLogLine
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return nil, fmt.Errorf("invalid docker-json log line: %w", err)
+	}
+	t, err := time.Parse(time.RFC3339Nano, raw.Time)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker-json log line timestamp %q: %w", raw.Time, err)
+	}
+	return &PodLogLine{
+		Time:    t,
+		Stream:  raw.Stream,
+		Partial: false,
+		Content: strings.TrimSuffix(raw.Log, "\n"),
+	}, nil
+}
+
+// parseCRILogLine parses the CRI framing: "<rfc3339 time> <stream> <tag> <content>",
+// e.g. "2023-01-01T00:00:00.000000000Z stdout F message". tag is "F" for a
+// full line and "P" for a partial line that continues on the next record.
+func parseCRILogLine(line []byte) (*PodLogLine, error) {
+	parts := strings.SplitN(string(line), " ", 4)
+	if len(parts) < 4 {
+		return nil, fmt.Errorf("invalid CRI log line %q", line)
+	}
+	t, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRI log line timestamp %q: %w", parts[0], err)
+	}
+	var partial bool
+	switch parts[2] {
+	case "F":
+		partial = false
+	case "P":
+		partial = true
+	default:
+		return nil, fmt.Errorf("invalid CRI log line tag %q", parts[2])
+	}
+	return &PodLogLine{
+		Time:    t,
+		Stream:  parts[1],
+		Partial: partial,
+		Content: parts[3],
+	}, nil
+}
+
+// PartialLineBuffer stitches the CRI "P" (partial) records of a single
+// container's log stream back into full lines, since the kubelet splits any
+// line above its internal buffer size across several on-disk records. Each
+// container being tailed needs its own PartialLineBuffer.
+type PartialLineBuffer struct {
+	pending strings.Builder
+}
+
+// Add feeds a parsed log line into the buffer. It returns the full line and
+// true once a non-partial record completes it; otherwise it returns false and
+// the caller should keep reading.
+func (b *PartialLineBuffer) Add(line *PodLogLine) (string, bool) {
+	b.pending.WriteString(line.Content)
+	if line.Partial {
+		return "", false
+	}
+	full := b.pending.String()
+	b.pending.Reset()
+	return full, true
+}
@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logs
+
+import "testing"
+
+func TestParsePodLogLineCRI(t *testing.T) {
+	line, err := ParsePodLogLine([]byte("2023-01-01T00:00:00.000000000Z stdout F hello world"))
+	if err != nil {
+		t.Fatalf("ParsePodLogLine() error = %v", err)
+	}
+	if line.Stream != PodLogStreamStdout || line.Partial || line.Content != "hello world" {
+		t.Errorf("ParsePodLogLine() = %+v", line)
+	}
+}
+
+func TestParsePodLogLineDockerJSON(t *testing.T) {
+	line, err := ParsePodLogLine([]byte(`{"log":"hello world\n","stream":"stderr","time":"2023-01-01T00:00:00.000000000Z"}`))
+	if err != nil {
+		t.Fatalf("ParsePodLogLine() error = %v", err)
+	}
+	if line.Stream != PodLogStreamStderr || line.Partial || line.Content != "hello world" {
+		t.Errorf("ParsePodLogLine() = %+v", line)
+	}
+}
+
+func TestParsePodLogLineInvalid(t *testing.T) {
+	if _, err := ParsePodLogLine(nil); err == nil {
+		t.Error("ParsePodLogLine() expected error for empty line")
+	}
+	if _, err := ParsePodLogLine([]byte("garbage")); err == nil {
+		t.Error("ParsePodLogLine() expected error for malformed CRI line")
+	}
+}
+
+func TestPartialLineBuffer(t *testing.T) {
+	var buf PartialLineBuffer
+
+	if full, done := buf.Add(&PodLogLine{Partial: true, Content: "hello "}); done {
+		t.Errorf("Add() = %q, %v, want not done", full, done)
+	}
+	full, done := buf.Add(&PodLogLine{Partial: false, Content: "world"})
+	if !done || full != "hello world" {
+		t.Errorf("Add() = %q, %v, want \"hello world\", true", full, done)
+	}
+
+	// buffer is reset after completing a line
+	full, done = buf.Add(&PodLogLine{Partial: false, Content: "next line"})
+	if !done || full != "next line" {
+		t.Errorf("Add() = %q, %v, want \"next line\", true", full, done)
+	}
+}